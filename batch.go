@@ -0,0 +1,199 @@
+package rajaongkir
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultCostWorkers is the worker pool size used by GetCostMulti when
+// the caller passes workers <= 0.
+const defaultCostWorkers = 5
+
+// GetProvincesByIDs fetches the full province catalog once and returns
+// the subset matching ids, keyed by province ID.
+func (r *RajaOngkir) GetProvincesByIDs(ids []string) (map[string]Province, error) {
+	return r.GetProvincesByIDsContext(context.Background(), ids)
+}
+
+// GetProvincesByIDsContext fetches the full province catalog once and
+// returns the subset matching ids, keyed by province ID, aborting
+// early if ctx is done.
+func (r *RajaOngkir) GetProvincesByIDsContext(ctx context.Context, ids []string) (map[string]Province, error) {
+	provinces, err := r.GetProvincesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	result := make(map[string]Province, len(ids))
+	for _, p := range provinces {
+		if wanted[p.ProvinceID] {
+			result[p.ProvinceID] = p
+		}
+	}
+	return result, nil
+}
+
+// GetCitiesByProvince fetches the full city catalog and returns the
+// subset belonging to provinceID.
+func (r *RajaOngkir) GetCitiesByProvince(provinceID string) ([]City, error) {
+	return r.GetCitiesByProvinceContext(context.Background(), provinceID)
+}
+
+// GetCitiesByProvinceContext fetches the full city catalog and returns
+// the subset belonging to provinceID, aborting early if ctx is done.
+func (r *RajaOngkir) GetCitiesByProvinceContext(ctx context.Context, provinceID string) ([]City, error) {
+	cities, err := r.GetCitiesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var filtered []City
+	for _, c := range cities {
+		if c.ProvinceID == provinceID {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+
+// GetSubdistrictsByCityIDs fetches the subdistricts of every city in
+// cityIDs concurrently, using the same default worker pool size as
+// GetCostMulti, and returns them keyed by city ID. The first error
+// encountered is returned, but results already fetched are still
+// returned alongside it.
+func (r *RajaOngkir) GetSubdistrictsByCityIDs(cityIDs []string) (map[string][]Subdistrict, error) {
+	return r.GetSubdistrictsByCityIDsContext(context.Background(), cityIDs)
+}
+
+// GetSubdistrictsByCityIDsContext fetches the subdistricts of every
+// city in cityIDs concurrently, the same way GetSubdistrictsByCityIDs
+// does, but aborts in-flight and queued lookups as soon as ctx is done.
+func (r *RajaOngkir) GetSubdistrictsByCityIDsContext(ctx context.Context, cityIDs []string) (map[string][]Subdistrict, error) {
+	result := make(map[string][]Subdistrict, len(cityIDs))
+	var mu sync.Mutex
+	var firstErr error
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workerCount(len(cityIDs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for cityID := range jobs {
+				subdistricts, err := r.GetSubdistrictsContext(ctx, cityID)
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					result[cityID] = subdistricts
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+dispatch:
+	for _, cityID := range cityIDs {
+		select {
+		case jobs <- cityID:
+		case <-ctx.Done():
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = ctx.Err()
+			}
+			mu.Unlock()
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return result, firstErr
+}
+
+// CostRequest is one GetCost call to run as part of GetCostMulti.
+type CostRequest struct {
+	Origin          string
+	OriginType      string
+	Destination     string
+	DestinationType string
+	Weight          int
+	Courier         string
+}
+
+// CostResult is the outcome of one CostRequest within GetCostMulti. Err
+// is set instead of failing the whole batch when an individual request
+// fails.
+type CostResult struct {
+	Request CostRequest
+	Costs   []Cost
+	Err     error
+}
+
+// GetCostMulti fans reqs out across a worker pool of size workers
+// (defaultCostWorkers if workers <= 0) and aggregates the results in
+// the same order as reqs. A failure in one request does not abort the
+// others; it is reported in the corresponding CostResult.Err.
+func (r *RajaOngkir) GetCostMulti(reqs []CostRequest, workers int) ([]CostResult, error) {
+	return r.GetCostMultiContext(context.Background(), reqs, workers)
+}
+
+// GetCostMultiContext fans reqs out across a worker pool the same way
+// GetCostMulti does, but aborts in-flight and queued requests as soon
+// as ctx is done; the corresponding CostResult.Err reports ctx.Err().
+func (r *RajaOngkir) GetCostMultiContext(ctx context.Context, reqs []CostRequest, workers int) ([]CostResult, error) {
+	if workers <= 0 {
+		workers = defaultCostWorkers
+	}
+	if workers > len(reqs) {
+		workers = len(reqs)
+	}
+
+	results := make([]CostResult, len(reqs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				req := reqs[idx]
+				costs, err := r.GetCostContext(ctx, req.Origin, req.OriginType, req.Destination, req.DestinationType, req.Weight, req.Courier)
+				results[idx] = CostResult{Request: req, Costs: costs, Err: err}
+			}
+		}()
+	}
+	dispatched := len(reqs)
+dispatch:
+	for i := range reqs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			dispatched = i
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := dispatched; i < len(reqs); i++ {
+		results[i] = CostResult{Request: reqs[i], Err: ctx.Err()}
+	}
+
+	return results, nil
+}
+
+// workerCount returns a worker pool size for n items, capped at
+// defaultCostWorkers.
+func workerCount(n int) int {
+	if n <= 0 {
+		return 1
+	}
+	if n > defaultCostWorkers {
+		return defaultCostWorkers
+	}
+	return n
+}
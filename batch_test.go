@@ -0,0 +1,76 @@
+package rajaongkir
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) *RajaOngkir {
+	t.Helper()
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return New("test-key", ts.URL, nil)
+}
+
+func TestGetSubdistrictsByCityIDsAggregatesPartialFailure(t *testing.T) {
+	r := newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		city := req.URL.Query().Get("city")
+		if city == "bad" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		resp := subdistrictsResponse{}
+		resp.Rajaongkir.Results = []Subdistrict{{SubdistrictID: "1", CityID: city, SubdistrictName: "Test"}}
+		json.NewEncoder(w).Encode(&resp)
+	})
+
+	result, err := r.GetSubdistrictsByCityIDs([]string{"good", "bad"})
+	if err == nil {
+		t.Fatal("expected an error from the failing city, got nil")
+	}
+	if _, ok := result["good"]; !ok {
+		t.Fatalf("expected result for the successful city, got %v", result)
+	}
+	if _, ok := result["bad"]; ok {
+		t.Fatalf("did not expect a result for the failing city, got %v", result)
+	}
+}
+
+func TestGetCostMultiReportsPerRequestErrors(t *testing.T) {
+	r := newTestServer(t, func(w http.ResponseWriter, req *http.Request) {
+		body := make([]byte, req.ContentLength)
+		req.Body.Read(body)
+		if strings.Contains(string(body), "courier=bad") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		resp := costResponse{}
+		resp.Rajaongkir.Status = status{Code: 200, Description: "OK"}
+		resp.Rajaongkir.Results = []carrierService{{Code: "jne", Costs: []Cost{{Service: "REG"}}}}
+		json.NewEncoder(w).Encode(&resp)
+	})
+
+	reqs := []CostRequest{
+		{Origin: "1", Destination: "2", Weight: 1000, Courier: "jne"},
+		{Origin: "1", Destination: "2", Weight: 1000, Courier: "bad"},
+	}
+	results, err := r.GetCostMulti(reqs, 2)
+	if err != nil {
+		t.Fatalf("GetCostMulti returned error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d, want 2", len(results))
+	}
+	if results[0].Err != nil {
+		t.Fatalf("results[0].Err = %v, want nil", results[0].Err)
+	}
+	if len(results[0].Costs) == 0 {
+		t.Fatalf("results[0].Costs is empty, want at least one cost")
+	}
+	if results[1].Err == nil {
+		t.Fatalf("results[1].Err = nil, want an error for the failing courier")
+	}
+}
@@ -0,0 +1,171 @@
+package rajaongkir
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// defaultCatalogTTL and defaultCostTTL are the TTLs applied by
+// CachePolicy.withDefaults when left unset.
+const (
+	defaultCatalogTTL = 24 * time.Hour
+	defaultCostTTL    = 15 * time.Minute
+)
+
+// Cache is the pluggable storage backend behind WithCache. Get reports
+// whether key was found and has not expired; Set stores val under key
+// for the given ttl.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, val []byte, ttl time.Duration)
+}
+
+// CachePolicy controls the TTL applied to catalog data (provinces,
+// cities, subdistricts) versus cost quotes.
+type CachePolicy struct {
+	CatalogTTL time.Duration
+	CostTTL    time.Duration
+}
+
+func (p CachePolicy) withDefaults() CachePolicy {
+	if p.CatalogTTL <= 0 {
+		p.CatalogTTL = defaultCatalogTTL
+	}
+	if p.CostTTL <= 0 {
+		p.CostTTL = defaultCostTTL
+	}
+	return p
+}
+
+// cacheConfig holds the per-call overrides applied by a CacheOption.
+type cacheConfig struct {
+	skip bool
+}
+
+// CacheOption overrides caching behaviour for a single call.
+type CacheOption func(*cacheConfig)
+
+// SkipCache bypasses the cache for a single call, forcing a round trip
+// to the upstream API and refreshing whatever was cached.
+func SkipCache() CacheOption {
+	return func(c *cacheConfig) { c.skip = true }
+}
+
+// getCached serves key from r.cache when present, otherwise calls fetch,
+// stores the result if caching is enabled, and decodes it into dest.
+// Concurrent callers for the same key share one fetch only when caching
+// is enabled and SkipCache is not set; otherwise each caller fetches.
+func (r *RajaOngkir) getCached(key string, ttl time.Duration, dest interface{}, opts []CacheOption, fetch func() (interface{}, error)) error {
+	cfg := cacheConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if r.cache == nil || cfg.skip {
+		val, err := fetch()
+		if err != nil {
+			return err
+		}
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		if r.cache != nil {
+			r.cache.Set(key, raw, ttl)
+		}
+		return json.Unmarshal(raw, dest)
+	}
+
+	if raw, ok := r.cache.Get(key); ok {
+		return json.Unmarshal(raw, dest)
+	}
+
+	v, err, _ := r.sfGroup.Do(key, func() (interface{}, error) {
+		val, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+		r.cache.Set(key, raw, ttl)
+		return raw, nil
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(v.([]byte), dest)
+}
+
+// lruEntry is one node of the LRUCache's internal doubly-linked list.
+type lruEntry struct {
+	key       string
+	val       []byte
+	expiresAt time.Time
+}
+
+// LRUCache is an in-memory, size-bounded Cache implementation with
+// per-entry TTL.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+// NewLRUCache creates an LRUCache holding at most capacity entries,
+// evicting the least recently used entry once full.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return entry.val, true
+}
+
+// Set implements Cache.
+func (c *LRUCache) Set(key string, val []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.val = val
+		entry.expiresAt = time.Now().Add(ttl)
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&lruEntry{key: key, val: val, expiresAt: time.Now().Add(ttl)})
+	c.items[key] = el
+
+	if c.capacity > 0 && c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package rajaongkir
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2"), time.Minute)
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected a to be present")
+	}
+
+	c.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("expected b to have been evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected a to still be present")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatal("expected c to be present")
+	}
+}
+
+func TestLRUCacheExpiresEntriesAfterTTL(t *testing.T) {
+	c := NewLRUCache(10)
+	c.Set("a", []byte("1"), time.Millisecond)
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected a to have expired")
+	}
+}
+
+func TestLRUCacheSetRefreshesExistingEntry(t *testing.T) {
+	c := NewLRUCache(1)
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("a", []byte("2"), time.Minute)
+
+	val, ok := c.Get("a")
+	if !ok {
+		t.Fatal("expected a to be present")
+	}
+	if string(val) != "2" {
+		t.Fatalf("val = %q, want %q", val, "2")
+	}
+}
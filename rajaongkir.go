@@ -3,10 +3,12 @@
 package rajaongkir
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"net/http"
 	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // List of endpoints according to https://rajaongkir.com/dokumentasi/starter
@@ -23,6 +25,43 @@ type RajaOngkir struct {
 	apiKey  string
 	baseURL string
 	client  *http.Client
+
+	cache       Cache
+	cachePolicy CachePolicy
+	sfGroup     singleflight.Group
+
+	retryPolicy RetryPolicy
+	rateLimiter RateLimiter
+}
+
+// Option configures optional behaviour on a RajaOngkir, such as caching.
+type Option func(*RajaOngkir)
+
+// WithCache enables caching of catalog and cost responses using c,
+// governed by policy. Concurrent identical requests are coalesced so
+// only one reaches the upstream API.
+func WithCache(c Cache, policy CachePolicy) Option {
+	return func(r *RajaOngkir) {
+		r.cache = c
+		r.cachePolicy = policy.withDefaults()
+	}
+}
+
+// WithRetry configures retrying of failed requests. By default a
+// RajaOngkir does not retry.
+func WithRetry(policy RetryPolicy) Option {
+	return func(r *RajaOngkir) {
+		r.retryPolicy = policy.withDefaults()
+	}
+}
+
+// WithRateLimiter makes the client self-throttle to rl before every
+// request, so it stays below the upstream tier limit instead of
+// discovering it via 429s.
+func WithRateLimiter(rl RateLimiter) Option {
+	return func(r *RajaOngkir) {
+		r.rateLimiter = rl
+	}
 }
 
 type query map[string]interface{}
@@ -147,101 +186,172 @@ type costResponse struct {
 
 // New initializes a new RajaOngkir struct
 // with a default client configured if none is specified
-func New(apiKey, baseURL string, client *http.Client) *RajaOngkir {
+func New(apiKey, baseURL string, client *http.Client, opts ...Option) *RajaOngkir {
 	if client == nil {
 		client = &http.Client{Timeout: defaultClientTimeout}
 	}
-	r := &RajaOngkir{apiKey, baseURL, client}
+	r := &RajaOngkir{apiKey: apiKey, baseURL: baseURL, client: client, retryPolicy: RetryPolicy{}.withDefaults()}
+	for _, opt := range opts {
+		opt(r)
+	}
 	return r
 }
 
-func checkStatus(status *status) error {
+// Close releases background resources held by a configured RateLimiter
+// (see WithRateLimiter). It is a no-op if none was configured.
+func (r *RajaOngkir) Close() error {
+	if r.rateLimiter != nil {
+		return r.rateLimiter.Close()
+	}
+	return nil
+}
+
+func checkStatus(status *status, endpoint string) error {
 	if status.Code >= 200 && status.Code < 300 {
 		return nil
 	}
-	return errors.New(status.Description)
+	return &APIError{Code: status.Code, Description: status.Description, Endpoint: endpoint}
 }
 
 // GetProvinces fetches the list of provinces
-func (r *RajaOngkir) GetProvinces() ([]Province, error) {
-	re := &provincesResponse{}
-	err := r.sendRequest(http.MethodGet, provinceEndpoint, "", re)
-	if err != nil {
-		return nil, err
-	}
-	err = checkStatus(&re.Rajaongkir.Status)
-	if err != nil {
-		return nil, err
-	}
-	provinces := re.Rajaongkir.Results
-	return provinces, nil
+func (r *RajaOngkir) GetProvinces(opts ...CacheOption) ([]Province, error) {
+	return r.GetProvincesContext(context.Background(), opts...)
+}
+
+// GetProvincesContext fetches the list of provinces, aborting early if
+// ctx is done.
+func (r *RajaOngkir) GetProvincesContext(ctx context.Context, opts ...CacheOption) ([]Province, error) {
+	var provinces []Province
+	err := r.getCached("provinces", r.cachePolicy.CatalogTTL, &provinces, opts, func() (interface{}, error) {
+		re := &provincesResponse{}
+		err := r.sendRequest(ctx, http.MethodGet, provinceEndpoint, "", re)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkStatus(&re.Rajaongkir.Status, provinceEndpoint); err != nil {
+			return nil, err
+		}
+		return re.Rajaongkir.Results, nil
+	})
+	return provinces, err
 }
 
 // GetProvince fetches a specific province
 // matching a given ID
-func (r *RajaOngkir) GetProvince(id string) (Province, error) {
-	re := &provinceResponse{}
+func (r *RajaOngkir) GetProvince(id string, opts ...CacheOption) (Province, error) {
+	return r.GetProvinceContext(context.Background(), id, opts...)
+}
+
+// GetProvinceContext fetches a specific province matching a given ID,
+// aborting early if ctx is done.
+func (r *RajaOngkir) GetProvinceContext(ctx context.Context, id string, opts ...CacheOption) (Province, error) {
+	var province Province
 	endpoint := fmt.Sprintf("%s?id=%s", provinceEndpoint, id)
-	err := r.sendRequest(http.MethodGet, endpoint, "", re)
-	if err != nil {
-		return Province{}, err
-	}
-	err = checkStatus(&re.Rajaongkir.Status)
-	if err != nil {
-		return Province{}, err
-	}
-	province := re.Rajaongkir.Results
-	return province, nil
+	err := r.getCached("province:"+id, r.cachePolicy.CatalogTTL, &province, opts, func() (interface{}, error) {
+		re := &provinceResponse{}
+		err := r.sendRequest(ctx, http.MethodGet, endpoint, "", re)
+		if err != nil {
+			return Province{}, err
+		}
+		if err := checkStatus(&re.Rajaongkir.Status, endpoint); err != nil {
+			return Province{}, err
+		}
+		return re.Rajaongkir.Results, nil
+	})
+	return province, err
 }
 
 // GetCities fetches the list of cities
-func (r *RajaOngkir) GetCities() ([]City, error) {
-	re := &citiesResponse{}
-	err := r.sendRequest(http.MethodGet, cityEndpoint, "", re)
+func (r *RajaOngkir) GetCities(opts ...CacheOption) ([]City, error) {
+	return r.GetCitiesContext(context.Background(), opts...)
+}
+
+// GetCitiesContext fetches the list of cities, aborting early if ctx is
+// done.
+func (r *RajaOngkir) GetCitiesContext(ctx context.Context, opts ...CacheOption) ([]City, error) {
+	var cities []City
+	err := r.getCached("cities", r.cachePolicy.CatalogTTL, &cities, opts, func() (interface{}, error) {
+		re := &citiesResponse{}
+		err := r.sendRequest(ctx, http.MethodGet, cityEndpoint, "", re)
+		if err != nil {
+			return nil, err
+		}
+		return re.Rajaongkir.Results, nil
+	})
 	if err != nil {
 		return []City{}, err
 	}
-	cities := re.Rajaongkir.Results
 	return cities, nil
 }
 
 // GetSubdistricts fetches the list of subdistricts of a city
-func (r *RajaOngkir) GetSubdistricts(city string) ([]Subdistrict, error) {
-	re := &subdistrictsResponse{}
-	endpoint := fmt.Sprintf("%s?city=%s", subdistrictEndpoint, city)
-	err := r.sendRequest(http.MethodGet, endpoint, "", re)
+func (r *RajaOngkir) GetSubdistricts(city string, opts ...CacheOption) ([]Subdistrict, error) {
+	return r.GetSubdistrictsContext(context.Background(), city, opts...)
+}
+
+// GetSubdistrictsContext fetches the list of subdistricts of a city,
+// aborting early if ctx is done.
+func (r *RajaOngkir) GetSubdistrictsContext(ctx context.Context, city string, opts ...CacheOption) ([]Subdistrict, error) {
+	var subdistricts []Subdistrict
+	err := r.getCached("subdistricts:"+city, r.cachePolicy.CatalogTTL, &subdistricts, opts, func() (interface{}, error) {
+		re := &subdistrictsResponse{}
+		endpoint := fmt.Sprintf("%s?city=%s", subdistrictEndpoint, city)
+		err := r.sendRequest(ctx, http.MethodGet, endpoint, "", re)
+		if err != nil {
+			return nil, err
+		}
+		return re.Rajaongkir.Results, nil
+	})
 	if err != nil {
 		return []Subdistrict{}, err
 	}
-	subdistricts := re.Rajaongkir.Results
 	return subdistricts, nil
 }
 
 // GetSubdistrict fetches one subdistrict data
-func (r *RajaOngkir) GetSubdistrict(city, subdistrictID string) (Subdistrict, error) {
-	re := &subdistrictResponse{}
-	endpoint := fmt.Sprintf("%s?city=%s&id=%s", subdistrictEndpoint, city, subdistrictID)
-	err := r.sendRequest(http.MethodGet, endpoint, "", re)
-	if err != nil {
-		return Subdistrict{}, err
-	}
-	subdistrict := re.Rajaongkir.Results
-	return subdistrict, nil
+func (r *RajaOngkir) GetSubdistrict(city, subdistrictID string, opts ...CacheOption) (Subdistrict, error) {
+	return r.GetSubdistrictContext(context.Background(), city, subdistrictID, opts...)
+}
+
+// GetSubdistrictContext fetches one subdistrict data, aborting early if
+// ctx is done.
+func (r *RajaOngkir) GetSubdistrictContext(ctx context.Context, city, subdistrictID string, opts ...CacheOption) (Subdistrict, error) {
+	var subdistrict Subdistrict
+	err := r.getCached("subdistrict:"+city+":"+subdistrictID, r.cachePolicy.CatalogTTL, &subdistrict, opts, func() (interface{}, error) {
+		re := &subdistrictResponse{}
+		endpoint := fmt.Sprintf("%s?city=%s&id=%s", subdistrictEndpoint, city, subdistrictID)
+		err := r.sendRequest(ctx, http.MethodGet, endpoint, "", re)
+		if err != nil {
+			return Subdistrict{}, err
+		}
+		return re.Rajaongkir.Results, nil
+	})
+	return subdistrict, err
 }
 
 // GetCost fetches the shipping rate
 // given the origin, destination, weight, and courier service
-func (r *RajaOngkir) GetCost(origin, originType, destination, destinationType string, weight int, courier string) ([]Cost, error) {
-	queryString := fmt.Sprintf("origin=%s&originType=%s&destination=%s&destinationType=%s&weight=%d&courier=%s", origin, originType, destination, destinationType, weight, courier)
-	re := &costResponse{}
-	err := r.sendRequest(http.MethodPost, costEndpoint, queryString, re)
-	if err != nil {
-		return nil, err
-	}
-	err = checkStatus(&re.Rajaongkir.Status)
-	if err != nil {
-		return nil, err
-	}
-	costs := re.Rajaongkir.Results[0].Costs
-	return costs, nil
+func (r *RajaOngkir) GetCost(origin, originType, destination, destinationType string, weight int, courier string, opts ...CacheOption) ([]Cost, error) {
+	return r.GetCostContext(context.Background(), origin, originType, destination, destinationType, weight, courier, opts...)
+}
+
+// GetCostContext fetches the shipping rate given the origin,
+// destination, weight, and courier service, aborting early if ctx is
+// done.
+func (r *RajaOngkir) GetCostContext(ctx context.Context, origin, originType, destination, destinationType string, weight int, courier string, opts ...CacheOption) ([]Cost, error) {
+	key := fmt.Sprintf("cost:%s:%s:%s:%s:%d:%s", origin, originType, destination, destinationType, weight, courier)
+	var costs []Cost
+	err := r.getCached(key, r.cachePolicy.CostTTL, &costs, opts, func() (interface{}, error) {
+		queryString := fmt.Sprintf("origin=%s&originType=%s&destination=%s&destinationType=%s&weight=%d&courier=%s", origin, originType, destination, destinationType, weight, courier)
+		re := &costResponse{}
+		err := r.sendRequest(ctx, http.MethodPost, costEndpoint, queryString, re)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkStatus(&re.Rajaongkir.Status, costEndpoint); err != nil {
+			return nil, err
+		}
+		return re.Rajaongkir.Results[0].Costs, nil
+	})
+	return costs, err
 }
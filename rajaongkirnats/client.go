@@ -0,0 +1,185 @@
+package rajaongkirnats
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pixelhousestudio/go-rajaongkir"
+)
+
+// defaultTimeout bounds how long the non-context Client methods wait for
+// a reply before giving up. The Context methods are bounded only by the
+// ctx they are given.
+const defaultTimeout = time.Second * 5
+
+// CostRequest mirrors the arguments of RajaOngkir.GetCost so it can be
+// marshalled onto SubjectCostCalculate.
+type CostRequest struct {
+	Origin          string `json:"origin"`
+	OriginType      string `json:"origin_type"`
+	Destination     string `json:"destination"`
+	DestinationType string `json:"destination_type"`
+	Weight          int    `json:"weight"`
+	Courier         string `json:"courier"`
+}
+
+// Client offers RajaOngkir's six lookup methods (GetProvinces,
+// GetProvince, GetCities, GetSubdistricts, GetSubdistrict, GetCost) and
+// their Context variants, backed by nats.Conn.Request instead of HTTP.
+// It has no CacheOption parameters and none of the batch methods.
+type Client struct {
+	nc      *nats.Conn
+	timeout time.Duration
+}
+
+// NewClient creates a Client that issues requests over nc with the
+// default timeout. Use ClientOption to override it.
+func NewClient(nc *nats.Conn, opts ...ClientOption) *Client {
+	c := &Client{nc: nc, timeout: defaultTimeout}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithTimeout overrides the default per-request timeout.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) { c.timeout = d }
+}
+
+func (c *Client) request(ctx context.Context, subject string, req interface{}, out interface{}) error {
+	var payload []byte
+	var err error
+	if req != nil {
+		payload, err = json.Marshal(req)
+		if err != nil {
+			return err
+		}
+	}
+	msg, err := c.nc.RequestWithContext(ctx, subject, payload)
+	if err != nil {
+		return err
+	}
+	return decode(msg, out)
+}
+
+// withDefaultTimeout derives a context bounded by c.timeout for the
+// legacy, non-context methods.
+func (c *Client) withDefaultTimeout() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+// GetProvinces fetches the list of provinces.
+func (c *Client) GetProvinces() ([]rajaongkir.Province, error) {
+	ctx, cancel := c.withDefaultTimeout()
+	defer cancel()
+	return c.GetProvincesContext(ctx)
+}
+
+// GetProvincesContext fetches the list of provinces, aborting early if
+// ctx is done.
+func (c *Client) GetProvincesContext(ctx context.Context) ([]rajaongkir.Province, error) {
+	var provinces []rajaongkir.Province
+	err := c.request(ctx, SubjectProvinceList, nil, &provinces)
+	return provinces, err
+}
+
+// GetProvince fetches a specific province matching a given ID.
+func (c *Client) GetProvince(id string) (rajaongkir.Province, error) {
+	ctx, cancel := c.withDefaultTimeout()
+	defer cancel()
+	return c.GetProvinceContext(ctx, id)
+}
+
+// GetProvinceContext fetches a specific province matching a given ID,
+// aborting early if ctx is done.
+func (c *Client) GetProvinceContext(ctx context.Context, id string) (rajaongkir.Province, error) {
+	var province rajaongkir.Province
+	req := struct {
+		ID string `json:"id"`
+	}{ID: id}
+	err := c.request(ctx, SubjectProvinceGet, req, &province)
+	return province, err
+}
+
+// GetCities fetches the list of cities.
+func (c *Client) GetCities() ([]rajaongkir.City, error) {
+	ctx, cancel := c.withDefaultTimeout()
+	defer cancel()
+	return c.GetCitiesContext(ctx)
+}
+
+// GetCitiesContext fetches the list of cities, aborting early if ctx is
+// done.
+func (c *Client) GetCitiesContext(ctx context.Context) ([]rajaongkir.City, error) {
+	var cities []rajaongkir.City
+	err := c.request(ctx, SubjectCityList, nil, &cities)
+	return cities, err
+}
+
+// GetSubdistricts fetches the list of subdistricts of a city.
+func (c *Client) GetSubdistricts(city string) ([]rajaongkir.Subdistrict, error) {
+	ctx, cancel := c.withDefaultTimeout()
+	defer cancel()
+	return c.GetSubdistrictsContext(ctx, city)
+}
+
+// GetSubdistrictsContext fetches the list of subdistricts of a city,
+// aborting early if ctx is done.
+func (c *Client) GetSubdistrictsContext(ctx context.Context, city string) ([]rajaongkir.Subdistrict, error) {
+	var subdistricts []rajaongkir.Subdistrict
+	req := struct {
+		City string `json:"city"`
+	}{City: city}
+	err := c.request(ctx, SubjectSubdistrictList, req, &subdistricts)
+	return subdistricts, err
+}
+
+// GetSubdistrict fetches one subdistrict data.
+func (c *Client) GetSubdistrict(city, subdistrictID string) (rajaongkir.Subdistrict, error) {
+	ctx, cancel := c.withDefaultTimeout()
+	defer cancel()
+	return c.GetSubdistrictContext(ctx, city, subdistrictID)
+}
+
+// GetSubdistrictContext fetches one subdistrict data, aborting early if
+// ctx is done.
+func (c *Client) GetSubdistrictContext(ctx context.Context, city, subdistrictID string) (rajaongkir.Subdistrict, error) {
+	var subdistrict rajaongkir.Subdistrict
+	req := struct {
+		City          string `json:"city"`
+		SubdistrictID string `json:"subdistrict_id"`
+	}{City: city, SubdistrictID: subdistrictID}
+	err := c.request(ctx, SubjectSubdistrictGet, req, &subdistrict)
+	return subdistrict, err
+}
+
+// GetCost fetches the shipping rate given the origin, destination,
+// weight, and courier service.
+func (c *Client) GetCost(origin, originType, destination, destinationType string, weight int, courier string) ([]rajaongkir.Cost, error) {
+	ctx, cancel := c.withDefaultTimeout()
+	defer cancel()
+	return c.GetCostContext(ctx, origin, originType, destination, destinationType, weight, courier)
+}
+
+// GetCostContext fetches the shipping rate given the origin,
+// destination, weight, and courier service, aborting early if ctx is
+// done.
+func (c *Client) GetCostContext(ctx context.Context, origin, originType, destination, destinationType string, weight int, courier string) ([]rajaongkir.Cost, error) {
+	var costs []rajaongkir.Cost
+	req := CostRequest{
+		Origin:          origin,
+		OriginType:      originType,
+		Destination:     destination,
+		DestinationType: destinationType,
+		Weight:          weight,
+		Courier:         courier,
+	}
+	err := c.request(ctx, SubjectCostCalculate, req, &costs)
+	return costs, err
+}
@@ -0,0 +1,67 @@
+// Package rajaongkirnats exposes a *rajaongkir.RajaOngkir over NATS
+// request/reply, so multiple services can share a single upstream API key
+// and cache instead of each holding HTTP credentials.
+package rajaongkirnats
+
+import (
+	"encoding/json"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Subjects used for the request/reply handlers registered by Server
+// and called by Client.
+const (
+	SubjectProvinceGet     = "rajaongkir.province.get"
+	SubjectProvinceList    = "rajaongkir.province.list"
+	SubjectCityList        = "rajaongkir.city.list"
+	SubjectSubdistrictList = "rajaongkir.subdistrict.list"
+	SubjectSubdistrictGet  = "rajaongkir.subdistrict.get"
+	SubjectCostCalculate   = "rajaongkir.cost.calculate"
+)
+
+// envelope is the JSON payload carried over every NATS reply. Exactly one
+// of Data or Error is populated.
+type envelope struct {
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+func reply(nc *nats.Conn, msg *nats.Msg, data interface{}, err error) {
+	env := envelope{}
+	if err != nil {
+		env.Error = err.Error()
+	} else {
+		raw, marshalErr := json.Marshal(data)
+		if marshalErr != nil {
+			env.Error = marshalErr.Error()
+		} else {
+			env.Data = raw
+		}
+	}
+	out, err := json.Marshal(env)
+	if err != nil {
+		return
+	}
+	msg.Respond(out)
+}
+
+func decode(msg *nats.Msg, v interface{}) error {
+	env := envelope{}
+	if err := json.Unmarshal(msg.Data, &env); err != nil {
+		return err
+	}
+	if env.Error != "" {
+		return errString(env.Error)
+	}
+	if v == nil || len(env.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(env.Data, v)
+}
+
+// errString lets decode return the upstream error message without pulling
+// in errors.New at every call site.
+type errString string
+
+func (e errString) Error() string { return string(e) }
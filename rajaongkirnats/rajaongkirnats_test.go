@@ -0,0 +1,143 @@
+package rajaongkirnats
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/pixelhousestudio/go-rajaongkir"
+)
+
+// startTestNATS starts an in-process nats-server and returns a connected
+// client, tearing both down at test cleanup.
+func startTestNATS(t *testing.T) *nats.Conn {
+	t.Helper()
+	opts := natsserver.Options{Host: "127.0.0.1", Port: -1}
+	srv, err := natsserver.NewServer(&opts)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats-server did not become ready")
+	}
+	t.Cleanup(srv.Shutdown)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("nats.Connect: %v", err)
+	}
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+func TestClientServerRoundTrip(t *testing.T) {
+	nc := startTestNATS(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"rajaongkir":{"status":{"code":200,"description":"OK"},"results":[{"province_id":"6","province":"DKI Jakarta"}]}}`))
+	}))
+	t.Cleanup(ts.Close)
+	ro := rajaongkir.New("test-key", ts.URL, nil)
+
+	server := NewServer(ro, nc)
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { server.Stop() })
+
+	client := NewClient(nc)
+	provinces, err := client.GetProvinces()
+	if err != nil {
+		t.Fatalf("GetProvinces: %v", err)
+	}
+	if len(provinces) != 1 || provinces[0].Province != "DKI Jakarta" {
+		t.Fatalf("GetProvinces = %v, want [DKI Jakarta]", provinces)
+	}
+}
+
+func TestClientSurfacesServerError(t *testing.T) {
+	nc := startTestNATS(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(ts.Close)
+	ro := rajaongkir.New("test-key", ts.URL, nil)
+
+	server := NewServer(ro, nc)
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { server.Stop() })
+
+	client := NewClient(nc)
+	if _, err := client.GetProvinces(); err == nil {
+		t.Fatal("expected an error from the envelope's Error field, got nil")
+	}
+}
+
+func TestClientGetProvincesContextHonorsCancellation(t *testing.T) {
+	nc := startTestNATS(t)
+	client := NewClient(nc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.GetProvincesContext(ctx); err == nil {
+		t.Fatal("expected an error from an already-cancelled context, got nil")
+	}
+}
+
+func TestServerStopClearsSubsOnSuccess(t *testing.T) {
+	nc := startTestNATS(t)
+	ro := rajaongkir.New("test-key", "http://127.0.0.1:0", nil)
+
+	server := NewServer(ro, nc)
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if err := server.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if server.subs != nil {
+		t.Fatalf("subs = %v, want nil after Stop", server.subs)
+	}
+}
+
+func TestServerStopStopsOnFirstUnsubscribeFailure(t *testing.T) {
+	nc := startTestNATS(t)
+	ro := rajaongkir.New("test-key", "http://127.0.0.1:0", nil)
+
+	alreadyUnsubscribed, err := nc.Subscribe(SubjectProvinceList, func(*nats.Msg) {})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := alreadyUnsubscribed.Unsubscribe(); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+
+	stillActive, err := nc.Subscribe(SubjectCityList, func(*nats.Msg) {})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	server := NewServer(ro, nc)
+	server.subs = []*nats.Subscription{alreadyUnsubscribed, stillActive}
+
+	if err := server.Stop(); err == nil {
+		t.Fatal("expected Stop to surface the failed Unsubscribe, got nil")
+	}
+	if server.subs == nil {
+		t.Fatal("subs should be left intact when Stop fails partway through")
+	}
+
+	// stillActive was never reached, so it is still subscribed; clean it
+	// up directly so it doesn't leak past the test.
+	stillActive.Unsubscribe()
+}
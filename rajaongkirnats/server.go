@@ -0,0 +1,113 @@
+package rajaongkirnats
+
+import (
+	"encoding/json"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pixelhousestudio/go-rajaongkir"
+)
+
+// Server wraps a *rajaongkir.RajaOngkir and exposes its methods as NATS
+// subject handlers, so several services can subscribe through one
+// upstream API key.
+type Server struct {
+	ro   *rajaongkir.RajaOngkir
+	nc   *nats.Conn
+	subs []*nats.Subscription
+}
+
+// NewServer creates a Server backed by ro and connected to nc. Call
+// Start to register the subject handlers.
+func NewServer(ro *rajaongkir.RajaOngkir, nc *nats.Conn) *Server {
+	return &Server{ro: ro, nc: nc}
+}
+
+// Start subscribes to every RajaOngkir subject. It is not safe to call
+// Start more than once on the same Server.
+func (s *Server) Start() error {
+	handlers := map[string]nats.MsgHandler{
+		SubjectProvinceList:    s.handleProvinces,
+		SubjectProvinceGet:     s.handleProvince,
+		SubjectCityList:        s.handleCities,
+		SubjectSubdistrictList: s.handleSubdistricts,
+		SubjectSubdistrictGet:  s.handleSubdistrict,
+		SubjectCostCalculate:   s.handleCost,
+	}
+	for subject, handler := range handlers {
+		sub, err := s.nc.Subscribe(subject, handler)
+		if err != nil {
+			s.Stop()
+			return err
+		}
+		s.subs = append(s.subs, sub)
+	}
+	return nil
+}
+
+// Stop unsubscribes from every subject registered by Start.
+func (s *Server) Stop() error {
+	for _, sub := range s.subs {
+		if err := sub.Unsubscribe(); err != nil {
+			return err
+		}
+	}
+	s.subs = nil
+	return nil
+}
+
+func (s *Server) handleProvinces(msg *nats.Msg) {
+	provinces, err := s.ro.GetProvinces()
+	reply(s.nc, msg, provinces, err)
+}
+
+func (s *Server) handleProvince(msg *nats.Msg) {
+	var req struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		reply(s.nc, msg, nil, err)
+		return
+	}
+	province, err := s.ro.GetProvince(req.ID)
+	reply(s.nc, msg, province, err)
+}
+
+func (s *Server) handleCities(msg *nats.Msg) {
+	cities, err := s.ro.GetCities()
+	reply(s.nc, msg, cities, err)
+}
+
+func (s *Server) handleSubdistricts(msg *nats.Msg) {
+	var req struct {
+		City string `json:"city"`
+	}
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		reply(s.nc, msg, nil, err)
+		return
+	}
+	subdistricts, err := s.ro.GetSubdistricts(req.City)
+	reply(s.nc, msg, subdistricts, err)
+}
+
+func (s *Server) handleSubdistrict(msg *nats.Msg) {
+	var req struct {
+		City          string `json:"city"`
+		SubdistrictID string `json:"subdistrict_id"`
+	}
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		reply(s.nc, msg, nil, err)
+		return
+	}
+	subdistrict, err := s.ro.GetSubdistrict(req.City, req.SubdistrictID)
+	reply(s.nc, msg, subdistrict, err)
+}
+
+func (s *Server) handleCost(msg *nats.Msg) {
+	var req CostRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		reply(s.nc, msg, nil, err)
+		return
+	}
+	costs, err := s.ro.GetCost(req.Origin, req.OriginType, req.Destination, req.DestinationType, req.Weight, req.Courier)
+	reply(s.nc, msg, costs, err)
+}
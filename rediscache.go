@@ -0,0 +1,35 @@
+package rajaongkir
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCache adapts a *redis.Client to the Cache interface so catalog
+// and cost data can be shared across process instances.
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisCache creates a RedisCache backed by client. Keys are stored
+// under prefix so the cache can share a Redis instance with other data.
+func NewRedisCache(client *redis.Client, prefix string) *RedisCache {
+	return &RedisCache{client: client, prefix: prefix}
+}
+
+// Get implements Cache.
+func (c *RedisCache) Get(key string) ([]byte, bool) {
+	val, err := c.client.Get(context.Background(), c.prefix+key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	return val, true
+}
+
+// Set implements Cache.
+func (c *RedisCache) Set(key string, val []byte, ttl time.Duration) {
+	c.client.Set(context.Background(), c.prefix+key, val, ttl)
+}
@@ -0,0 +1,344 @@
+package rajaongkir
+
+import (
+	"context"
+	"encoding/gob"
+	"os"
+	"sort"
+	"strings"
+)
+
+// noiseWords are Indonesian address tokens stripped before matching
+// free text against the province/city/subdistrict hierarchy.
+var noiseWords = map[string]bool{
+	"jl":   true,
+	"jl.":  true,
+	"kel":  true,
+	"kel.": true,
+	"kec":  true,
+	"kec.": true,
+	"kab":  true,
+	"kab.": true,
+	"kota": true,
+}
+
+// maxEditDistance is the Levenshtein distance allowed when fuzzy
+// matching a token of at least minFuzzyTokenLen characters.
+const (
+	maxEditDistance  = 2
+	minFuzzyTokenLen = 5
+)
+
+// Resolver builds an in-memory index of the province/city/subdistrict
+// catalog so free-text addresses can be resolved and suggestions can be
+// served without round-tripping to the upstream API on every call.
+type Resolver struct {
+	ro *RajaOngkir
+
+	provinces    []Province
+	cities       []City
+	subdistricts []Subdistrict
+
+	citiesByProvince   map[string][]City
+	subdistrictsByCity map[string][]Subdistrict
+}
+
+// NewResolver creates a Resolver backed by ro. Call Refresh before using
+// it, or load a previously persisted index with LoadIndex.
+func NewResolver(ro *RajaOngkir) *Resolver {
+	return &Resolver{ro: ro}
+}
+
+// Refresh rebuilds the index from the upstream API.
+func (r *Resolver) Refresh(ctx context.Context) error {
+	provinces, err := r.ro.GetProvincesContext(ctx)
+	if err != nil {
+		return err
+	}
+	cities, err := r.ro.GetCitiesContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	citiesByProvince := make(map[string][]City)
+	for _, c := range cities {
+		citiesByProvince[c.ProvinceID] = append(citiesByProvince[c.ProvinceID], c)
+	}
+
+	subdistrictsByCity := make(map[string][]Subdistrict)
+	for _, c := range cities {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		subdistricts, err := r.ro.GetSubdistrictsContext(ctx, c.CityID)
+		if err != nil {
+			return err
+		}
+		subdistrictsByCity[c.CityID] = subdistricts
+	}
+
+	r.provinces = provinces
+	r.cities = cities
+	r.citiesByProvince = citiesByProvince
+	r.subdistrictsByCity = subdistrictsByCity
+	var all []Subdistrict
+	for _, subdistricts := range subdistrictsByCity {
+		all = append(all, subdistricts...)
+	}
+	r.subdistricts = all
+	return nil
+}
+
+// index is the gob-serializable snapshot persisted by SaveIndex and
+// restored by LoadIndex.
+type index struct {
+	Provinces          []Province
+	Cities             []City
+	Subdistricts       []Subdistrict
+	CitiesByProvince   map[string][]City
+	SubdistrictsByCity map[string][]Subdistrict
+}
+
+// SaveIndex persists the current index to path so a cold start doesn't
+// need to call Refresh against the upstream API.
+func (r *Resolver) SaveIndex(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	idx := index{
+		Provinces:          r.provinces,
+		Cities:             r.cities,
+		Subdistricts:       r.subdistricts,
+		CitiesByProvince:   r.citiesByProvince,
+		SubdistrictsByCity: r.subdistrictsByCity,
+	}
+	return gob.NewEncoder(f).Encode(&idx)
+}
+
+// LoadIndex restores a previously persisted index from path.
+func (r *Resolver) LoadIndex(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var idx index
+	if err := gob.NewDecoder(f).Decode(&idx); err != nil {
+		return err
+	}
+	r.provinces = idx.Provinces
+	r.cities = idx.Cities
+	r.subdistricts = idx.Subdistricts
+	r.citiesByProvince = idx.CitiesByProvince
+	r.subdistrictsByCity = idx.SubdistrictsByCity
+	return nil
+}
+
+// SuggestCity returns up to limit cities whose name starts with, or is a
+// close fuzzy match of, prefix.
+func (r *Resolver) SuggestCity(prefix string, limit int) []City {
+	normalizedPrefix := normalize(prefix)
+	type scored struct {
+		city  City
+		score int
+	}
+	var matches []scored
+	for _, c := range r.cities {
+		name := normalize(c.CityName)
+		switch {
+		case strings.HasPrefix(name, normalizedPrefix):
+			matches = append(matches, scored{c, 0})
+		case len(normalizedPrefix) >= minFuzzyTokenLen:
+			if d := levenshtein(name, normalizedPrefix); d <= maxEditDistance {
+				matches = append(matches, scored{c, d + 1})
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score < matches[j].score })
+	if limit < 0 {
+		limit = 0
+	}
+	if limit > len(matches) {
+		limit = len(matches)
+	}
+	cities := make([]City, limit)
+	for i := 0; i < limit; i++ {
+		cities[i] = matches[i].city
+	}
+	return cities
+}
+
+// SuggestSubdistrict returns up to limit subdistricts of cityID whose
+// name starts with, or is a close fuzzy match of, prefix.
+func (r *Resolver) SuggestSubdistrict(cityID, prefix string, limit int) []Subdistrict {
+	normalizedPrefix := normalize(prefix)
+	type scored struct {
+		subdistrict Subdistrict
+		score       int
+	}
+	var matches []scored
+	for _, s := range r.subdistrictsByCity[cityID] {
+		name := normalize(s.SubdistrictName)
+		switch {
+		case strings.HasPrefix(name, normalizedPrefix):
+			matches = append(matches, scored{s, 0})
+		case len(normalizedPrefix) >= minFuzzyTokenLen:
+			if d := levenshtein(name, normalizedPrefix); d <= maxEditDistance {
+				matches = append(matches, scored{s, d + 1})
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score < matches[j].score })
+	if limit < 0 {
+		limit = 0
+	}
+	if limit > len(matches) {
+		limit = len(matches)
+	}
+	subdistricts := make([]Subdistrict, limit)
+	for i := 0; i < limit; i++ {
+		subdistricts[i] = matches[i].subdistrict
+	}
+	return subdistricts
+}
+
+// ResolveAddress tokenizes freeText, strips Indonesian address noise
+// words, and matches the remaining tokens against the hierarchy from
+// largest (province) to smallest (subdistrict). At each level every
+// candidate is scored by how much of its own name is covered by the
+// input tokens, and the best-scoring candidate wins, so "Jakarta
+// Selatan" beats "Jakarta Barat" for an input that only shares the
+// "jakarta" token with the latter. confidence reflects the fraction of
+// levels that were matched.
+func (r *Resolver) ResolveAddress(freeText string) (province Province, city City, subdistrict Subdistrict, confidence float64, err error) {
+	tokens := tokenize(freeText)
+	if len(tokens) == 0 {
+		return Province{}, City{}, Subdistrict{}, 0, nil
+	}
+
+	matched := 0
+
+	bestCoverage := 0.0
+	for _, p := range r.provinces {
+		if c := tokenCoverage(tokens, tokenize(p.Province)); c > bestCoverage {
+			bestCoverage = c
+			province = p
+		}
+	}
+	if bestCoverage > 0 {
+		matched++
+	}
+
+	candidateCities := r.cities
+	if province.ProvinceID != "" {
+		candidateCities = r.citiesByProvince[province.ProvinceID]
+	}
+	bestCoverage = 0
+	for _, c := range candidateCities {
+		if cov := tokenCoverage(tokens, tokenize(c.CityName)); cov > bestCoverage {
+			bestCoverage = cov
+			city = c
+		}
+	}
+	if bestCoverage > 0 {
+		matched++
+	}
+
+	candidateSubdistricts := r.subdistricts
+	if city.CityID != "" {
+		candidateSubdistricts = r.subdistrictsByCity[city.CityID]
+	}
+	bestCoverage = 0
+	for _, s := range candidateSubdistricts {
+		if cov := tokenCoverage(tokens, tokenize(s.SubdistrictName)); cov > bestCoverage {
+			bestCoverage = cov
+			subdistrict = s
+		}
+	}
+	if bestCoverage > 0 {
+		matched++
+	}
+
+	confidence = float64(matched) / 3
+	return province, city, subdistrict, confidence, nil
+}
+
+// tokenCoverage reports the fraction of candidate's tokens that are
+// matched, exactly or within maxEditDistance for tokens of
+// minFuzzyTokenLen or longer, by some token in input.
+func tokenCoverage(input, candidate []string) float64 {
+	if len(candidate) == 0 {
+		return 0
+	}
+	matched := 0
+	for _, ct := range candidate {
+		for _, t := range input {
+			if t == ct || (len(t) >= minFuzzyTokenLen && len(ct) >= minFuzzyTokenLen && levenshtein(t, ct) <= maxEditDistance) {
+				matched++
+				break
+			}
+		}
+	}
+	return float64(matched) / float64(len(candidate))
+}
+
+// tokenize lowercases s, splits it on whitespace, and drops Indonesian
+// address noise words.
+func tokenize(s string) []string {
+	fields := strings.Fields(normalize(s))
+	tokens := fields[:0]
+	for _, f := range fields {
+		if noiseWords[f] {
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	la, lb := len(a), len(b)
+	if la == 0 {
+		return lb
+	}
+	if lb == 0 {
+		return la
+	}
+	prev := make([]int, lb+1)
+	curr := make([]int, lb+1)
+	for j := 0; j <= lb; j++ {
+		prev[j] = j
+	}
+	for i := 1; i <= la; i++ {
+		curr[0] = i
+		for j := 1; j <= lb; j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(curr[j-1]+1, prev[j]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[lb]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
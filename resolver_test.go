@@ -0,0 +1,91 @@
+package rajaongkir
+
+import "testing"
+
+func testResolver() *Resolver {
+	r := &Resolver{
+		provinces: []Province{
+			{ProvinceID: "6", Province: "DKI Jakarta"},
+		},
+		cities: []City{
+			{CityID: "151", ProvinceID: "6", CityName: "Jakarta Barat"},
+			{CityID: "153", ProvinceID: "6", CityName: "Jakarta Selatan"},
+		},
+		citiesByProvince: map[string][]City{
+			"6": {
+				{CityID: "151", ProvinceID: "6", CityName: "Jakarta Barat"},
+				{CityID: "153", ProvinceID: "6", CityName: "Jakarta Selatan"},
+			},
+		},
+		subdistrictsByCity: map[string][]Subdistrict{
+			"153": {
+				{SubdistrictID: "1", CityID: "153", SubdistrictName: "Kebayoran Baru"},
+			},
+		},
+	}
+	return r
+}
+
+func TestResolveAddressDisambiguatesSharedTokens(t *testing.T) {
+	r := testResolver()
+
+	province, city, _, confidence, err := r.ResolveAddress("Jl ABC Jakarta Selatan")
+	if err != nil {
+		t.Fatalf("ResolveAddress returned error: %v", err)
+	}
+	if province.Province != "DKI Jakarta" {
+		t.Fatalf("province = %q, want DKI Jakarta", province.Province)
+	}
+	if city.CityName != "Jakarta Selatan" {
+		t.Fatalf("city = %q, want Jakarta Selatan", city.CityName)
+	}
+	if confidence <= 0 {
+		t.Fatalf("confidence = %v, want > 0", confidence)
+	}
+}
+
+func TestResolveAddressMatchesSubdistrictWithinResolvedCity(t *testing.T) {
+	r := testResolver()
+
+	_, city, subdistrict, _, err := r.ResolveAddress("Kebayoran Baru Jakarta Selatan")
+	if err != nil {
+		t.Fatalf("ResolveAddress returned error: %v", err)
+	}
+	if city.CityName != "Jakarta Selatan" {
+		t.Fatalf("city = %q, want Jakarta Selatan", city.CityName)
+	}
+	if subdistrict.SubdistrictName != "Kebayoran Baru" {
+		t.Fatalf("subdistrict = %q, want Kebayoran Baru", subdistrict.SubdistrictName)
+	}
+}
+
+func TestSuggestCityPrefersPrefixOverFuzzyMatch(t *testing.T) {
+	r := testResolver()
+	// "Jakarta Selatn" is one edit away from the input, so it is only a
+	// fuzzy match; it comes first in the catalog, so the prefix match
+	// must win on score.
+	r.cities = append([]City{{CityID: "999", ProvinceID: "6", CityName: "Jakarta Selatn"}}, r.cities...)
+
+	got := r.SuggestCity("jakarta selatan", 1)
+	if len(got) != 1 || got[0].CityName != "Jakarta Selatan" {
+		t.Fatalf("SuggestCity = %v, want [Jakarta Selatan]", got)
+	}
+}
+
+func TestSuggestCityClampsNegativeLimit(t *testing.T) {
+	r := testResolver()
+
+	got := r.SuggestCity("jak", -1)
+	if len(got) != 0 {
+		t.Fatalf("SuggestCity with negative limit = %v, want empty", got)
+	}
+}
+
+func TestSuggestSubdistrictClampsNegativeLimit(t *testing.T) {
+	r := testResolver()
+
+	got := r.SuggestSubdistrict("153", "keb", -1)
+	if len(got) != 0 {
+		t.Fatalf("SuggestSubdistrict with negative limit = %v, want empty", got)
+	}
+}
@@ -0,0 +1,240 @@
+package rajaongkir
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// APIError is returned when the upstream API reports a non-2xx status,
+// either at the HTTP level (rate limiting) or in the embedded
+// rajaongkir status object (e.g. an invalid key or destination). It
+// lets callers distinguish those cases instead of matching on a bare
+// error string.
+type APIError struct {
+	Code        int
+	Description string
+	Endpoint    string
+
+	// RetryAfter is set when the upstream sent a Retry-After header,
+	// typically alongside a 429 or 503.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("rajaongkir: %s: %d %s", e.Endpoint, e.Code, e.Description)
+}
+
+// RetryPolicy controls whether and how a RajaOngkir retries a failed
+// request. The zero value disables retries (MaxAttempts defaults to 1).
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// RetryOn decides whether a request that failed with status and err
+	// should be retried. The default retries 429 and 503 only.
+	RetryOn func(status int, err error) bool
+}
+
+const (
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+)
+
+func defaultRetryOn(status int, err error) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 1
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaultInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+	if p.RetryOn == nil {
+		p.RetryOn = defaultRetryOn
+	}
+	return p
+}
+
+// RateLimiter lets a RajaOngkir self-throttle to stay below the
+// upstream tier limit. Wait blocks until a request may proceed or ctx
+// is done. Close releases any background resources the limiter holds;
+// a RajaOngkir calls it from its own Close, so a RateLimiter passed to
+// WithRateLimiter must not be shared between clients or closed twice.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+	Close() error
+}
+
+// tokenBucketLimiter is the default RateLimiter implementation: a
+// simple token bucket refilled at a fixed rate.
+type tokenBucketLimiter struct {
+	tokens    chan struct{}
+	interval  time.Duration
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewTokenBucketLimiter creates a RateLimiter that allows burst requests
+// immediately and then refills one token every interval. Call Close (or
+// close the RajaOngkir it is attached to via WithRateLimiter) once the
+// limiter is no longer needed, to stop its refill goroutine.
+func NewTokenBucketLimiter(burst int, interval time.Duration) RateLimiter {
+	l := &tokenBucketLimiter{
+		tokens:   make(chan struct{}, burst),
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+	for i := 0; i < burst; i++ {
+		l.tokens <- struct{}{}
+	}
+	go l.refill()
+	return l
+}
+
+func (l *tokenBucketLimiter) refill() {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			select {
+			case l.tokens <- struct{}{}:
+			default:
+			}
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// Close stops the refill goroutine. It is safe to call more than once.
+func (l *tokenBucketLimiter) Close() error {
+	l.closeOnce.Do(func() { close(l.stop) })
+	return nil
+}
+
+func (l *tokenBucketLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sendRequest performs method against endpoint with body as the
+// request payload, decoding the JSON response into out. It honors
+// ctx cancellation, waits on r.rateLimiter before every attempt, and retries
+// according to r.retryPolicy, with exponential backoff and jitter,
+// honoring any Retry-After header on 429/503 responses.
+func (r *RajaOngkir) sendRequest(ctx context.Context, method, endpoint, body string, out interface{}) error {
+	policy := r.retryPolicy.withDefaults()
+	backoff := policy.InitialBackoff
+
+	var lastErr error
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		if r.rateLimiter != nil {
+			if err := r.rateLimiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		status, err := r.doOnce(ctx, method, endpoint, body, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if attempt == policy.MaxAttempts || !policy.RetryOn(status, err) {
+			return err
+		}
+
+		wait := backoff
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			wait = apiErr.RetryAfter
+		} else {
+			wait += time.Duration(rand.Int63n(int64(backoff) + 1))
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+	return lastErr
+}
+
+// doOnce issues a single HTTP request and decodes its JSON body into
+// out. It returns the HTTP status code alongside any error so the
+// caller can apply a RetryPolicy.
+func (r *RajaOngkir) doOnce(ctx context.Context, method, endpoint, body string, out interface{}) (int, error) {
+	var bodyReader *bytes.Buffer
+	if body != "" {
+		bodyReader = bytes.NewBufferString(body)
+	} else {
+		bodyReader = bytes.NewBuffer(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+endpoint, bodyReader)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("key", r.apiKey)
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		return resp.StatusCode, &APIError{
+			Code:        resp.StatusCode,
+			Description: http.StatusText(resp.StatusCode),
+			Endpoint:    endpoint,
+			RetryAfter:  parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, nil
+}
+
+// parseRetryAfter parses a Retry-After header value expressed in
+// seconds. An unparsable or empty value yields zero.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(v)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}